@@ -0,0 +1,128 @@
+// Package config assembles the tool's settings from command-line flags, an
+// optional config file, and environment variables, so the migration can be
+// pointed at a remote MariaDB instance and any supported destination
+// without editing source.
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Config holds every setting needed to run a migration.
+type Config struct {
+	SourceDSN     string
+	SourceDriver  string
+	SourceDB      string
+	TargetDSN     string
+	TargetDriver  string
+	Tables        []string
+	ExcludeTables []string
+	FK            bool
+	BatchSize     int
+	Parallel      int
+	LogLevel      string
+
+	CreateSchema bool
+	DryRun       bool
+	Verify       bool
+}
+
+// Load parses args (typically os.Args[1:]) along with an optional config
+// file and WMS_-prefixed environment variables into a Config. Precedence,
+// highest first: flags explicitly passed on the command line, environment
+// variables, the config file, then the defaults below.
+func Load(args []string) (*Config, error) {
+	flags := pflag.NewFlagSet("wallabag-mysql-to-sqlite", pflag.ContinueOnError)
+
+	configFile := flags.String("config", "", "path to an optional YAML or TOML config file")
+	flags.String("source-dsn", "wallabag:wallabag@tcp(127.0.0.1:3306)/wallabag?charset=utf8mb4&parseTime=true",
+		"source DSN, interpreted according to --source-driver (the MariaDB default supports parseTime, TLS params, etc.)")
+	flags.String("source-driver", "mysql", "source dialect: mysql, sqlite, or postgres")
+	flags.String("source-db", "wallabag", "source database name (schema, for postgres)")
+	flags.String("target-dsn", "./wallabag.sqlite", "target DSN or file path, interpreted according to --target-driver")
+	flags.String("target-driver", "sqlite", "target dialect: sqlite, mysql, or postgres")
+	flags.StringSlice("tables", nil, "only migrate these tables (default: all tables)")
+	flags.StringSlice("exclude-tables", nil, "never migrate these tables")
+	flags.Bool("fk", true, "respect foreign-key dependency order when migrating in parallel")
+	flags.Int("batch-size", 500, "rows committed to the destination per transaction")
+	flags.Int("parallel", 1, "number of tables to migrate concurrently")
+	flags.String("log-level", "info", "log verbosity: debug adds per-batch progress lines; any other value (info, warn, error) logs the same")
+	flags.Bool("create-schema", false, "create the destination schema from MariaDB's INFORMATION_SCHEMA before copying data")
+	flags.Bool("dry-run", false, "print planned row counts and insert templates without writing anything")
+	flags.Bool("verify", false, "compare an already-migrated database against the source instead of migrating")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetEnvPrefix("wms")
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	if err := v.BindPFlags(flags); err != nil {
+		return nil, fmt.Errorf("binding flags failed: %w", err)
+	}
+
+	if *configFile != "" {
+		v.SetConfigFile(*configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("reading config file %s failed: %w", *configFile, err)
+		}
+	}
+
+	return &Config{
+		SourceDSN:     v.GetString("source-dsn"),
+		SourceDriver:  v.GetString("source-driver"),
+		SourceDB:      v.GetString("source-db"),
+		TargetDSN:     v.GetString("target-dsn"),
+		TargetDriver:  v.GetString("target-driver"),
+		Tables:        v.GetStringSlice("tables"),
+		ExcludeTables: v.GetStringSlice("exclude-tables"),
+		FK:            v.GetBool("fk"),
+		BatchSize:     v.GetInt("batch-size"),
+		Parallel:      v.GetInt("parallel"),
+		LogLevel:      v.GetString("log-level"),
+		CreateSchema:  v.GetBool("create-schema"),
+		DryRun:        v.GetBool("dry-run"),
+		Verify:        v.GetBool("verify"),
+	}, nil
+}
+
+// FilterTables applies an include list (if non-empty, only these tables are
+// kept, in the given order) and an exclude list (these are always dropped)
+// to tables, returning the filtered result.
+func FilterTables(tables, include, exclude []string) []string {
+	if len(include) > 0 {
+		known := make(map[string]bool, len(tables))
+		for _, t := range tables {
+			known[t] = true
+		}
+		filtered := make([]string, 0, len(include))
+		for _, t := range include {
+			if known[t] {
+				filtered = append(filtered, t)
+			}
+		}
+		tables = filtered
+	}
+
+	if len(exclude) == 0 {
+		return tables
+	}
+	excluded := make(map[string]bool, len(exclude))
+	for _, t := range exclude {
+		excluded[t] = true
+	}
+	filtered := make([]string, 0, len(tables))
+	for _, t := range tables {
+		if !excluded[t] {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
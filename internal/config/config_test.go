@@ -0,0 +1,55 @@
+package config
+
+import "testing"
+
+func TestFilterTablesNoFilters(t *testing.T) {
+	got := FilterTables([]string{"a", "b", "c"}, nil, nil)
+	want := []string{"a", "b", "c"}
+	if !equalSlices(got, want) {
+		t.Errorf("FilterTables(no filters) = %v, want %v", got, want)
+	}
+}
+
+func TestFilterTablesIncludeOrdersByIncludeList(t *testing.T) {
+	got := FilterTables([]string{"a", "b", "c"}, []string{"c", "a"}, nil)
+	want := []string{"c", "a"}
+	if !equalSlices(got, want) {
+		t.Errorf("FilterTables(include) = %v, want %v", got, want)
+	}
+}
+
+func TestFilterTablesIncludeDropsUnknown(t *testing.T) {
+	got := FilterTables([]string{"a", "b"}, []string{"a", "missing"}, nil)
+	want := []string{"a"}
+	if !equalSlices(got, want) {
+		t.Errorf("FilterTables(include with unknown table) = %v, want %v", got, want)
+	}
+}
+
+func TestFilterTablesExclude(t *testing.T) {
+	got := FilterTables([]string{"a", "b", "c"}, nil, []string{"b"})
+	want := []string{"a", "c"}
+	if !equalSlices(got, want) {
+		t.Errorf("FilterTables(exclude) = %v, want %v", got, want)
+	}
+}
+
+func TestFilterTablesIncludeAndExclude(t *testing.T) {
+	got := FilterTables([]string{"a", "b", "c"}, []string{"c", "a", "b"}, []string{"b"})
+	want := []string{"c", "a"}
+	if !equalSlices(got, want) {
+		t.Errorf("FilterTables(include+exclude) = %v, want %v", got, want)
+	}
+}
+
+func equalSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+// Package dialect abstracts over the database-specific bits of the
+// migration: how to list tables, read column metadata, quote identifiers,
+// build placeholders, and map a column to a Go scan type. main.go drives the
+// migration generically against a source and a destination Dialect, so any
+// pair of supported backends can be migrated between without special-casing.
+package dialect
+
+import "database/sql"
+
+// ColumnInfo holds metadata for a database column.
+type ColumnInfo struct {
+	Name string
+	Type string // dialect-native type string (e.g. "int", "varchar", "datetime")
+}
+
+// ScanKind identifies the Go type a column's values should be scanned into,
+// independent of which dialect it came from.
+type ScanKind int
+
+const (
+	ScanNullString ScanKind = iota
+	ScanNullInt64
+	ScanNullFloat64
+	ScanNullBool
+	ScanNullTime
+	ScanBytes
+)
+
+// Dialect is implemented once per supported database backend. It wraps an
+// open *sql.DB and knows how to describe and address that database's
+// tables in its own SQL dialect.
+type Dialect interface {
+	// Name identifies the dialect, e.g. "mysql", "sqlite", "postgres".
+	Name() string
+
+	// DB returns the underlying connection, for running the generic
+	// migration queries (Begin, Query, Exec) that don't vary by dialect.
+	DB() *sql.DB
+
+	// ListTables returns the table names in dbName, in no particular order.
+	ListTables(dbName string) ([]string, error)
+
+	// ColumnInfo returns the ordered column metadata for table.
+	ColumnInfo(dbName, table string) ([]ColumnInfo, error)
+
+	// QuoteIdent quotes name as an identifier for this dialect.
+	QuoteIdent(name string) string
+
+	// Placeholder returns the bind placeholder for the i-th argument
+	// (0-indexed) of a query, e.g. "?" or "$1".
+	Placeholder(i int) string
+
+	// MapColumnType returns the ScanKind to use when reading col's values.
+	MapColumnType(col ColumnInfo) ScanKind
+
+	// RenderInsert builds a multi-row upsert-style INSERT statement for
+	// table with the given columns and rows value tuples, using this
+	// dialect's placeholder and conflict syntax. pkColumns, when non-empty,
+	// names the destination's primary key columns, which dialects that
+	// require an explicit conflict target (e.g. Postgres's ON CONFLICT)
+	// need in order to actually update existing rows instead of just
+	// ignoring them.
+	RenderInsert(table string, columns []string, rows int, pkColumns []string) string
+
+	// MaxBatchParams returns the maximum number of bind parameters this
+	// dialect/driver allows in a single statement, used to size how many
+	// rows RenderInsert can safely pack into one multi-row INSERT.
+	MaxBatchParams() int
+}
+
+// NewScanDest allocates a pointer to the Go type that matches kind, ready to
+// pass directly to sql.Rows.Scan as a destination. Using the concrete
+// sql.NullXxx types (rather than scanning into *interface{}) is what makes
+// NULL handling actually work: database/sql special-cases *interface{}
+// destinations by storing the driver's raw value verbatim, so a
+// pre-populated zero value there would have no effect.
+func NewScanDest(kind ScanKind) interface{} {
+	switch kind {
+	case ScanNullInt64:
+		return new(sql.NullInt64)
+	case ScanNullFloat64:
+		return new(sql.NullFloat64)
+	case ScanNullBool:
+		return new(sql.NullBool)
+	case ScanNullTime:
+		return new(sql.NullTime)
+	case ScanBytes:
+		return new([]byte)
+	default:
+		return new(sql.NullString)
+	}
+}
@@ -0,0 +1,42 @@
+package dialect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMySQLRenderInsertUsesOnDuplicateKeyUpdate(t *testing.T) {
+	m := &MySQL{}
+	got := m.RenderInsert("widget", []string{"id", "name"}, 2, []string{"id"})
+
+	if !strings.HasPrefix(got, "INSERT INTO `widget` (`id`, `name`) VALUES (?, ?), (?, ?)") {
+		t.Errorf("unexpected INSERT clause: %s", got)
+	}
+	if !strings.Contains(got, "ON DUPLICATE KEY UPDATE `id` = VALUES(`id`), `name` = VALUES(`name`)") {
+		t.Errorf("expected ON DUPLICATE KEY UPDATE for every column, got: %s", got)
+	}
+}
+
+func TestPostgresRenderInsertOnConflictUpdatesNonKeyColumns(t *testing.T) {
+	p := &Postgres{}
+	got := p.RenderInsert("widget", []string{"id", "name", "size"}, 1, []string{"id"})
+
+	if !strings.HasPrefix(got, `INSERT INTO "widget" ("id", "name", "size") VALUES ($1, $2, $3)`) {
+		t.Errorf("unexpected INSERT clause: %s", got)
+	}
+	if !strings.Contains(got, `ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "size" = EXCLUDED."size"`) {
+		t.Errorf("expected ON CONFLICT (id) DO UPDATE SET over the non-key columns, got: %s", got)
+	}
+	if strings.Contains(got, `"id" = EXCLUDED."id"`) {
+		t.Errorf("the conflict target column itself should not be reassigned, got: %s", got)
+	}
+}
+
+func TestPostgresRenderInsertFallsBackWithoutPrimaryKey(t *testing.T) {
+	p := &Postgres{}
+	got := p.RenderInsert("widget", []string{"name"}, 1, nil)
+
+	if !strings.HasSuffix(got, "ON CONFLICT DO NOTHING") {
+		t.Errorf("expected ON CONFLICT DO NOTHING without a known primary key, got: %s", got)
+	}
+}
@@ -0,0 +1,170 @@
+package dialect
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// MySQL implements Dialect for MariaDB/MySQL, used as the source side of the
+// original wallabag migration and, once a driver handle exists, equally well
+// as a destination.
+type MySQL struct {
+	db *sql.DB
+}
+
+// NewMySQL wraps an already-open MariaDB/MySQL connection.
+func NewMySQL(db *sql.DB) *MySQL {
+	return &MySQL{db: db}
+}
+
+func (m *MySQL) Name() string { return "mysql" }
+func (m *MySQL) DB() *sql.DB  { return m.db }
+func (m *MySQL) QuoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+func (m *MySQL) Placeholder(i int) string { return "?" }
+
+// ListTables retrieves a list of table names from the given MariaDB database.
+func (m *MySQL) ListTables(dbName string) ([]string, error) {
+	rows, err := m.db.Query("SELECT table_name FROM information_schema.tables WHERE table_schema = ?", dbName)
+	if err != nil {
+		return nil, fmt.Errorf("querying tables failed: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	var tableName string
+	for rows.Next() {
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("scanning table name failed: %w", err)
+		}
+		tables = append(tables, tableName)
+	}
+	return tables, nil
+}
+
+// ColumnInfo retrieves column names and types for a given table.
+func (m *MySQL) ColumnInfo(dbName, table string) ([]ColumnInfo, error) {
+	rows, err := m.db.Query(`
+        SELECT COLUMN_NAME, DATA_TYPE
+        FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+        ORDER BY ORDINAL_POSITION
+    `, dbName, table)
+	if err != nil {
+		return nil, fmt.Errorf("querying column info for table %s failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	var colName, colType string
+	for rows.Next() {
+		if err := rows.Scan(&colName, &colType); err != nil {
+			return nil, fmt.Errorf("scanning column info for table %s failed: %w", table, err)
+		}
+		columns = append(columns, ColumnInfo{Name: colName, Type: colType})
+	}
+	return columns, nil
+}
+
+// MapColumnType maps a MariaDB data type string to an appropriate ScanKind.
+// This is crucial for handling NULL values and preparing for type conversions.
+func (m *MySQL) MapColumnType(col ColumnInfo) ScanKind {
+	switch strings.ToLower(col.Type) {
+	case "int", "tinyint", "smallint", "mediumint", "bigint":
+		return ScanNullInt64
+	case "float", "double", "decimal", "numeric":
+		return ScanNullFloat64
+	case "varchar", "text", "tinytext", "mediumtext", "longtext", "char", "json":
+		return ScanNullString // JSON will be read as strings/bytes
+	case "blob", "longblob", "mediumblob", "tinyblob":
+		return ScanBytes
+	case "datetime", "timestamp", "date":
+		return ScanNullTime
+	case "boolean": // MariaDB's BOOLEAN is a TINYINT(1)
+		return ScanNullBool
+	default:
+		log.Printf("Warning: Unhandled MariaDB type '%s'. Attempting to scan as string.", col.Type)
+		return ScanNullString
+	}
+}
+
+// RenderInsert builds a multi-row MySQL upsert using ON DUPLICATE KEY
+// UPDATE, which is MariaDB/MySQL's equivalent of SQLite's INSERT OR REPLACE.
+// Unlike Postgres, MySQL needs no explicit conflict target, so pkColumns is
+// unused here.
+func (m *MySQL) RenderInsert(table string, columns []string, rows int, pkColumns []string) string {
+	quoted := make([]string, len(columns))
+	updates := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = m.QuoteIdent(col)
+		updates[i] = fmt.Sprintf("%s = VALUES(%s)", m.QuoteIdent(col), m.QuoteIdent(col))
+	}
+
+	valueTuples := make([]string, rows)
+	for r := 0; r < rows; r++ {
+		placeholders := make([]string, len(columns))
+		for i := range columns {
+			placeholders[i] = m.Placeholder(r*len(columns) + i)
+		}
+		valueTuples[r] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s",
+		m.QuoteIdent(table), strings.Join(quoted, ", "), strings.Join(valueTuples, ", "), strings.Join(updates, ", "))
+}
+
+// MaxBatchParams returns MySQL/MariaDB's hard limit of 65535 placeholders
+// per prepared statement.
+func (m *MySQL) MaxBatchParams() int { return 65535 }
+
+// PrimaryKeyColumn returns the name of table's primary key column, if (and
+// only if) it has exactly one and that column is an integer type. Resuming a
+// migration by "WHERE col > ?" only makes sense for a monotonically
+// comparable integer key; a composite primary key, no primary key at all, or
+// a non-integer single-column key (e.g. a varchar) all report "", which
+// forces a full re-copy instead of a (silently wrong) resume.
+func (m *MySQL) PrimaryKeyColumn(dbName, table string) (string, error) {
+	rows, err := m.db.Query(`
+		SELECT kcu.COLUMN_NAME, c.DATA_TYPE
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+		JOIN INFORMATION_SCHEMA.COLUMNS c
+		  ON c.TABLE_SCHEMA = kcu.TABLE_SCHEMA AND c.TABLE_NAME = kcu.TABLE_NAME AND c.COLUMN_NAME = kcu.COLUMN_NAME
+		WHERE kcu.TABLE_SCHEMA = ? AND kcu.TABLE_NAME = ? AND kcu.CONSTRAINT_NAME = 'PRIMARY'
+		ORDER BY kcu.ORDINAL_POSITION
+	`, dbName, table)
+	if err != nil {
+		return "", fmt.Errorf("querying primary key for table %s failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	var pkColumns []string
+	var pkTypes []string
+	for rows.Next() {
+		var col, dataType string
+		if err := rows.Scan(&col, &dataType); err != nil {
+			return "", fmt.Errorf("scanning primary key column for table %s failed: %w", table, err)
+		}
+		pkColumns = append(pkColumns, col)
+		pkTypes = append(pkTypes, dataType)
+	}
+	if len(pkColumns) != 1 || !isIntegerType(pkTypes[0]) {
+		return "", nil
+	}
+	return pkColumns[0], nil
+}
+
+// isIntegerType reports whether a MariaDB DATA_TYPE is an integer type,
+// mirroring the integer case of MapColumnType.
+func isIntegerType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "int", "tinyint", "smallint", "mediumint", "bigint":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,199 @@
+package dialect
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Postgres implements Dialect for PostgreSQL, usable as either source or
+// destination of a migration.
+type Postgres struct {
+	db *sql.DB
+}
+
+// NewPostgres wraps an already-open PostgreSQL connection (driver "pgx").
+func NewPostgres(db *sql.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+func (p *Postgres) Name() string { return "postgres" }
+func (p *Postgres) DB() *sql.DB  { return p.db }
+func (p *Postgres) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+func (p *Postgres) Placeholder(i int) string { return fmt.Sprintf("$%d", i+1) }
+
+// ListTables returns the table names in the given schema (dbName is used as
+// the schema name; pass "public" for the default schema).
+func (p *Postgres) ListTables(dbName string) ([]string, error) {
+	if dbName == "" {
+		dbName = "public"
+	}
+	rows, err := p.db.Query(`SELECT table_name FROM information_schema.tables WHERE table_schema = $1`, dbName)
+	if err != nil {
+		return nil, fmt.Errorf("querying tables failed: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	var tableName string
+	for rows.Next() {
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("scanning table name failed: %w", err)
+		}
+		tables = append(tables, tableName)
+	}
+	return tables, nil
+}
+
+// ColumnInfo returns the ordered column metadata for table in the given
+// schema (dbName), using the column's declared UDT name as its type string.
+func (p *Postgres) ColumnInfo(dbName, table string) ([]ColumnInfo, error) {
+	if dbName == "" {
+		dbName = "public"
+	}
+	rows, err := p.db.Query(`
+        SELECT column_name, udt_name
+        FROM information_schema.columns
+        WHERE table_schema = $1 AND table_name = $2
+        ORDER BY ordinal_position
+    `, dbName, table)
+	if err != nil {
+		return nil, fmt.Errorf("querying column info for table %s failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	var colName, colType string
+	for rows.Next() {
+		if err := rows.Scan(&colName, &colType); err != nil {
+			return nil, fmt.Errorf("scanning column info for table %s failed: %w", table, err)
+		}
+		columns = append(columns, ColumnInfo{Name: colName, Type: colType})
+	}
+	return columns, nil
+}
+
+// MapColumnType maps a PostgreSQL udt_name to an appropriate ScanKind.
+func (p *Postgres) MapColumnType(col ColumnInfo) ScanKind {
+	switch strings.ToLower(col.Type) {
+	case "int2", "int4", "int8":
+		return ScanNullInt64
+	case "float4", "float8", "numeric":
+		return ScanNullFloat64
+	case "bool":
+		return ScanNullBool
+	case "bytea":
+		return ScanBytes
+	case "timestamp", "timestamptz", "date":
+		return ScanNullTime
+	default:
+		return ScanNullString // text, varchar, json, jsonb, etc.
+	}
+}
+
+// RenderInsert builds a multi-row PostgreSQL upsert. When pkColumns is
+// known, it renders a real ON CONFLICT (pk...) DO UPDATE SET that refreshes
+// every non-key column from the incoming row, so a resumed or re-run
+// migration actually overwrites stale rows instead of leaving them in
+// place. Without a usable conflict target, it falls back to ON CONFLICT DO
+// NOTHING, which is safe but not idempotent in the face of source changes.
+func (p *Postgres) RenderInsert(table string, columns []string, rows int, pkColumns []string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = p.QuoteIdent(col)
+	}
+
+	valueTuples := make([]string, rows)
+	for r := 0; r < rows; r++ {
+		placeholders := make([]string, len(columns))
+		for i := range columns {
+			placeholders[i] = p.Placeholder(r*len(columns) + i)
+		}
+		valueTuples[r] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		p.QuoteIdent(table), strings.Join(quoted, ", "), strings.Join(valueTuples, ", "))
+
+	if len(pkColumns) == 0 {
+		return insert + " ON CONFLICT DO NOTHING"
+	}
+
+	conflictCols := make([]string, len(pkColumns))
+	pkSet := make(map[string]bool, len(pkColumns))
+	for i, col := range pkColumns {
+		conflictCols[i] = p.QuoteIdent(col)
+		pkSet[col] = true
+	}
+
+	var updates []string
+	for _, col := range columns {
+		if pkSet[col] {
+			continue
+		}
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", p.QuoteIdent(col), p.QuoteIdent(col)))
+	}
+	if len(updates) == 0 {
+		return insert + fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", strings.Join(conflictCols, ", "))
+	}
+
+	return insert + fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(conflictCols, ", "), strings.Join(updates, ", "))
+}
+
+// MaxBatchParams returns PostgreSQL's hard limit of 65535 bind parameters
+// per statement.
+func (p *Postgres) MaxBatchParams() int { return 65535 }
+
+// PrimaryKeyColumn returns the name of table's primary key column, if (and
+// only if) it has exactly one and that column is an integer type, mirroring
+// MySQL.PrimaryKeyColumn so Postgres sources get the same resume/verify
+// fidelity as MariaDB ones.
+func (p *Postgres) PrimaryKeyColumn(dbName, table string) (string, error) {
+	if dbName == "" {
+		dbName = "public"
+	}
+	rows, err := p.db.Query(`
+        SELECT kcu.column_name, c.udt_name
+        FROM information_schema.table_constraints tc
+        JOIN information_schema.key_column_usage kcu
+          ON kcu.constraint_name = tc.constraint_name AND kcu.table_schema = tc.table_schema AND kcu.table_name = tc.table_name
+        JOIN information_schema.columns c
+          ON c.table_schema = kcu.table_schema AND c.table_name = kcu.table_name AND c.column_name = kcu.column_name
+        WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = $1 AND tc.table_name = $2
+        ORDER BY kcu.ordinal_position
+    `, dbName, table)
+	if err != nil {
+		return "", fmt.Errorf("querying primary key for table %s failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	var pkColumns []string
+	var pkTypes []string
+	for rows.Next() {
+		var col, udtName string
+		if err := rows.Scan(&col, &udtName); err != nil {
+			return "", fmt.Errorf("scanning primary key column for table %s failed: %w", table, err)
+		}
+		pkColumns = append(pkColumns, col)
+		pkTypes = append(pkTypes, udtName)
+	}
+	if len(pkColumns) != 1 || !isPostgresIntegerType(pkTypes[0]) {
+		return "", nil
+	}
+	return pkColumns[0], nil
+}
+
+// isPostgresIntegerType reports whether a PostgreSQL udt_name is an integer
+// type, mirroring the integer case of MapColumnType.
+func isPostgresIntegerType(udtName string) bool {
+	switch strings.ToLower(udtName) {
+	case "int2", "int4", "int8":
+		return true
+	default:
+		return false
+	}
+}
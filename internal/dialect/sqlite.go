@@ -0,0 +1,133 @@
+package dialect
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLite implements Dialect for SQLite databases, the original destination
+// of the wallabag migration.
+type SQLite struct {
+	db *sql.DB
+}
+
+// NewSQLite wraps an already-open SQLite connection.
+func NewSQLite(db *sql.DB) *SQLite {
+	return &SQLite{db: db}
+}
+
+func (s *SQLite) Name() string { return "sqlite" }
+func (s *SQLite) DB() *sql.DB  { return s.db }
+func (s *SQLite) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+func (s *SQLite) Placeholder(i int) string { return "?" }
+
+// ListTables returns the names of every user table in the SQLite database.
+// dbName is unused: SQLite has no notion of multiple schemas here.
+func (s *SQLite) ListTables(dbName string) ([]string, error) {
+	rows, err := s.db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		return nil, fmt.Errorf("querying tables failed: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	var tableName string
+	for rows.Next() {
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("scanning table name failed: %w", err)
+		}
+		tables = append(tables, tableName)
+	}
+	return tables, nil
+}
+
+// ColumnInfo returns column names and declared types for table, read via
+// PRAGMA table_info.
+func (s *SQLite) ColumnInfo(dbName, table string) ([]ColumnInfo, error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", s.QuoteIdent(table)))
+	if err != nil {
+		return nil, fmt.Errorf("querying column info for table %s failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var cid int
+		var name, colType string
+		var notNull int
+		var dfltValue sql.NullString
+		var pk int
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return nil, fmt.Errorf("scanning column info for table %s failed: %w", table, err)
+		}
+		columns = append(columns, ColumnInfo{Name: name, Type: colType})
+	}
+	return columns, nil
+}
+
+// MapColumnType maps a SQLite declared type to an appropriate ScanKind,
+// following SQLite's type affinity rules.
+func (s *SQLite) MapColumnType(col ColumnInfo) ScanKind {
+	switch {
+	case strings.Contains(strings.ToUpper(col.Type), "INT"):
+		return ScanNullInt64
+	case strings.Contains(strings.ToUpper(col.Type), "REAL"),
+		strings.Contains(strings.ToUpper(col.Type), "FLOA"),
+		strings.Contains(strings.ToUpper(col.Type), "DOUB"):
+		return ScanNullFloat64
+	case strings.Contains(strings.ToUpper(col.Type), "BLOB"):
+		return ScanBytes
+	default:
+		return ScanNullString // TEXT affinity, which covers dates/JSON too
+	}
+}
+
+// RenderInsert builds a multi-row INSERT OR REPLACE upsert, which SQLite
+// supports natively, sized to rows value tuples. INSERT OR REPLACE needs no
+// explicit conflict target, so pkColumns is unused here.
+func (s *SQLite) RenderInsert(table string, columns []string, rows int, pkColumns []string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = s.QuoteIdent(col)
+	}
+
+	valueTuples := make([]string, rows)
+	for r := 0; r < rows; r++ {
+		placeholders := make([]string, len(columns))
+		for i := range columns {
+			placeholders[i] = s.Placeholder(r*len(columns) + i)
+		}
+		valueTuples[r] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	return fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES %s",
+		s.QuoteIdent(table), strings.Join(quoted, ", "), strings.Join(valueTuples, ", "))
+}
+
+// MaxBatchParams returns SQLite's default SQLITE_MAX_VARIABLE_NUMBER limit
+// of 999 bind parameters per statement.
+func (s *SQLite) MaxBatchParams() int { return 999 }
+
+// ApplyImportPragmas tunes this connection for bulk loading: WAL journaling
+// (so readers never block the writer), relaxed fsync durability, in-memory
+// temporary storage, and a busy_timeout so that concurrent writers (e.g.
+// --parallel workers, each with their own connection to the same file) retry
+// instead of failing immediately with SQLITE_BUSY.
+func (s *SQLite) ApplyImportPragmas() error {
+	for _, pragma := range []string{
+		"PRAGMA journal_mode=WAL",
+		"PRAGMA synchronous=NORMAL",
+		"PRAGMA temp_store=MEMORY",
+		"PRAGMA busy_timeout=30000",
+	} {
+		if _, err := s.db.Exec(pragma); err != nil {
+			return fmt.Errorf("applying %q failed: %w", pragma, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// supportedDialects lists the dialects Migration.SQL actually renders DDL
+// for; any other dialectName would silently apply nothing.
+var supportedDialects = map[string]bool{
+	"sqlite": true,
+}
+
+// Apply executes each migration's rendered DDL for dialectName against db, in
+// order. Migrations that render an empty string for dialectName are skipped.
+func Apply(db *sql.DB, dialectName string, migrations []Migration) error {
+	if !supportedDialects[dialectName] {
+		return fmt.Errorf("schema package cannot generate DDL for dialect %q (only sqlite is implemented)", dialectName)
+	}
+	for _, m := range migrations {
+		stmt := m.SQL(dialectName)
+		if stmt == "" {
+			continue
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("applying schema migration failed: %w\n%s", err, stmt)
+		}
+	}
+	return nil
+}
+
+// CreateTable reads table's definition from MariaDB and creates the
+// equivalent table (plus its secondary indexes) in the destination database.
+func CreateTable(mariaDB, dst *sql.DB, dstDialectName, dbName, table string) error {
+	t, err := ReadTable(mariaDB, dbName, table)
+	if err != nil {
+		return fmt.Errorf("reading schema for table %s failed: %w", table, err)
+	}
+	return Apply(dst, dstDialectName, TableMigrations(t))
+}
@@ -0,0 +1,201 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Migration is a single DDL statement that knows how to render itself for a
+// given destination dialect. This mirrors Grafana's migration.Migration
+// interface (a Sql(dialect) string method per migration step) so that future
+// schema changes - adding a column, renaming one, adding an index - can be
+// layered on top of the initial CreateTable/CreateIndex migrations as their
+// own Migration implementations.
+type Migration interface {
+	// SQL renders the migration's DDL for dialectName, or returns "" if the
+	// migration has nothing to do for that dialect.
+	SQL(dialectName string) string
+}
+
+// CreateTableMigration creates table's structure, including its primary key.
+type CreateTableMigration struct {
+	Table *Table
+}
+
+// SQL implements Migration.
+func (m *CreateTableMigration) SQL(dialectName string) string {
+	switch dialectName {
+	case "sqlite":
+		return sqliteCreateTable(m.Table)
+	default:
+		return ""
+	}
+}
+
+// CreateIndexMigration creates one of table's secondary indexes.
+type CreateIndexMigration struct {
+	Table *Table
+	Index Index
+}
+
+// SQL implements Migration.
+func (m *CreateIndexMigration) SQL(dialectName string) string {
+	switch dialectName {
+	case "sqlite":
+		return sqliteCreateIndex(m.Table.Name, m.Index)
+	default:
+		return ""
+	}
+}
+
+// TableMigrations returns the ordered set of migrations that create table
+// from scratch: one CreateTableMigration followed by one CreateIndexMigration
+// per secondary index. Foreign keys are embedded directly in the CREATE
+// TABLE statement, as SQLite requires.
+func TableMigrations(table *Table) []Migration {
+	migrations := []Migration{&CreateTableMigration{Table: table}}
+	for _, idx := range table.Indexes {
+		migrations = append(migrations, &CreateIndexMigration{Table: table, Index: idx})
+	}
+	return migrations
+}
+
+// quoteSQLiteIdent quotes name as a SQLite identifier.
+func quoteSQLiteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// sqliteColumnType maps a MariaDB DATA_TYPE to its SQLite storage class.
+func sqliteColumnType(col Column) string {
+	switch strings.ToLower(col.Type) {
+	case "tinyint": // tinyint(1) is MariaDB's boolean; either way INTEGER fits
+		return "INTEGER"
+	case "int", "smallint", "mediumint", "bigint":
+		return "INTEGER"
+	case "float", "double", "decimal", "numeric":
+		return "REAL"
+	case "datetime", "timestamp", "date":
+		return "TEXT"
+	case "json":
+		return "TEXT"
+	case "blob", "longblob", "mediumblob", "tinyblob":
+		return "BLOB"
+	default:
+		return "TEXT" // varchar, char, text family, enum, etc.
+	}
+}
+
+// sqliteCreateTable renders a CREATE TABLE IF NOT EXISTS statement for table,
+// honoring primary keys (including the INTEGER PRIMARY KEY AUTOINCREMENT
+// rowid-alias form), NOT NULL, DEFAULT, and foreign keys.
+func sqliteCreateTable(table *Table) string {
+	singleIntAutoPK := len(table.PrimaryKey) == 1 && isAutoIncrementColumn(table, table.PrimaryKey[0])
+
+	var lines []string
+	for _, col := range table.Columns {
+		line := fmt.Sprintf("  %s %s", quoteSQLiteIdent(col.Name), sqliteColumnType(col))
+		if singleIntAutoPK && col.Name == table.PrimaryKey[0] {
+			line += " PRIMARY KEY AUTOINCREMENT"
+		} else if !col.Nullable {
+			line += " NOT NULL"
+		}
+		if col.Default.Valid && !col.AutoIncrement {
+			line += fmt.Sprintf(" DEFAULT %s", sqliteDefaultLiteral(col.Default.String))
+		}
+		lines = append(lines, line)
+	}
+
+	if !singleIntAutoPK && len(table.PrimaryKey) > 0 {
+		quoted := make([]string, len(table.PrimaryKey))
+		for i, col := range table.PrimaryKey {
+			quoted[i] = quoteSQLiteIdent(col)
+		}
+		lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(quoted, ", ")))
+	}
+
+	for _, fk := range table.ForeignKeys {
+		lines = append(lines, "  "+sqliteForeignKeyClause(fk))
+	}
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n)", quoteSQLiteIdent(table.Name), strings.Join(lines, ",\n"))
+}
+
+// sqliteCreateIndex renders a CREATE INDEX/CREATE UNIQUE INDEX statement for
+// one of tableName's secondary indexes.
+func sqliteCreateIndex(tableName string, idx Index) string {
+	quotedCols := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		quotedCols[i] = quoteSQLiteIdent(col)
+	}
+	unique := ""
+	if idx.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s (%s)",
+		unique, quoteSQLiteIdent(idx.Name), quoteSQLiteIdent(tableName), strings.Join(quotedCols, ", "))
+}
+
+// sqliteForeignKeyClause renders a FOREIGN KEY (...) REFERENCES ... clause,
+// including ON UPDATE/ON DELETE rules when MariaDB reported anything other
+// than the default NO ACTION.
+func sqliteForeignKeyClause(fk ForeignKey) string {
+	cols := make([]string, len(fk.Columns))
+	for i, col := range fk.Columns {
+		cols[i] = quoteSQLiteIdent(col)
+	}
+	refCols := make([]string, len(fk.RefColumns))
+	for i, col := range fk.RefColumns {
+		refCols[i] = quoteSQLiteIdent(col)
+	}
+
+	clause := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		strings.Join(cols, ", "), quoteSQLiteIdent(fk.RefTable), strings.Join(refCols, ", "))
+	if fk.OnUpdate != "" && fk.OnUpdate != "NO ACTION" {
+		clause += " ON UPDATE " + fk.OnUpdate
+	}
+	if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" {
+		clause += " ON DELETE " + fk.OnDelete
+	}
+	return clause
+}
+
+// sqliteDefaultLiteral renders a MariaDB COLUMN_DEFAULT value as a SQLite
+// literal. MariaDB reports string/date defaults unquoted, so anything that
+// isn't a recognized SQL keyword or a plain number needs quoting.
+func sqliteDefaultLiteral(def string) string {
+	upper := strings.ToUpper(def)
+	if upper == "NULL" || upper == "CURRENT_TIMESTAMP" {
+		return upper
+	}
+	if isNumericLiteral(def) {
+		return def
+	}
+	return "'" + strings.ReplaceAll(def, "'", "''") + "'"
+}
+
+func isNumericLiteral(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == '-' && i == 0 {
+			continue
+		}
+		if r == '.' {
+			continue
+		}
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAutoIncrementColumn(table *Table, name string) bool {
+	for _, col := range table.Columns {
+		if col.Name == name {
+			return col.AutoIncrement
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSqliteCreateTableSingleIntAutoPK(t *testing.T) {
+	table := &Table{
+		Name: "entry",
+		Columns: []Column{
+			{Name: "id", Type: "int", AutoIncrement: true},
+			{Name: "title", Type: "varchar", Nullable: false},
+			{Name: "content", Type: "text", Nullable: true},
+		},
+		PrimaryKey: []string{"id"},
+	}
+
+	got := sqliteCreateTable(table)
+
+	if !strings.Contains(got, `"id" INTEGER PRIMARY KEY AUTOINCREMENT`) {
+		t.Errorf("expected single-column integer PK to use AUTOINCREMENT rowid alias, got:\n%s", got)
+	}
+	if !strings.Contains(got, `"title" TEXT NOT NULL`) {
+		t.Errorf("expected non-nullable column to render NOT NULL, got:\n%s", got)
+	}
+	if strings.Contains(got, `"content" TEXT NOT NULL`) {
+		t.Errorf("expected nullable column to omit NOT NULL, got:\n%s", got)
+	}
+	if strings.Contains(got, "PRIMARY KEY (") {
+		t.Errorf("expected no separate PRIMARY KEY clause when using AUTOINCREMENT, got:\n%s", got)
+	}
+}
+
+func TestSqliteCreateTableCompositePK(t *testing.T) {
+	table := &Table{
+		Name: "entry_tag",
+		Columns: []Column{
+			{Name: "entry_id", Type: "int"},
+			{Name: "tag_id", Type: "int"},
+		},
+		PrimaryKey: []string{"entry_id", "tag_id"},
+	}
+
+	got := sqliteCreateTable(table)
+
+	if !strings.Contains(got, `PRIMARY KEY ("entry_id", "tag_id")`) {
+		t.Errorf("expected composite PK to render as a separate PRIMARY KEY clause, got:\n%s", got)
+	}
+}
+
+func TestSqliteDefaultLiteral(t *testing.T) {
+	cases := []struct {
+		def  string
+		want string
+	}{
+		{"NULL", "NULL"},
+		{"CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP"},
+		{"42", "42"},
+		{"-1.5", "-1.5"},
+		{"archive", "'archive'"},
+		{"O'Brien", "'O''Brien'"},
+	}
+	for _, c := range cases {
+		if got := sqliteDefaultLiteral(c.def); got != c.want {
+			t.Errorf("sqliteDefaultLiteral(%q) = %q, want %q", c.def, got, c.want)
+		}
+	}
+}
+
+func TestIsNumericLiteral(t *testing.T) {
+	cases := []struct {
+		s    string
+		want bool
+	}{
+		{"", false},
+		{"0", true},
+		{"42", true},
+		{"-1", true},
+		{"3.14", true},
+		{"-3.14", true},
+		{"abc", false},
+		{"1-2", false},
+	}
+	for _, c := range cases {
+		if got := isNumericLiteral(c.s); got != c.want {
+			t.Errorf("isNumericLiteral(%q) = %v, want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestCreateTableMigrationSQLUnsupportedDialect(t *testing.T) {
+	m := &CreateTableMigration{Table: &Table{Name: "entry"}}
+	if got := m.SQL("postgres"); got != "" {
+		t.Errorf("expected empty SQL for unsupported dialect, got %q", got)
+	}
+}
@@ -0,0 +1,202 @@
+// Package schema reads a MariaDB table's definition from INFORMATION_SCHEMA
+// and translates it into DDL for a destination dialect, so the migration
+// tool can create the destination schema instead of requiring it to exist
+// upfront.
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Column describes a single MariaDB column in enough detail to render an
+// equivalent destination column definition.
+type Column struct {
+	Name          string
+	Type          string // MariaDB DATA_TYPE, e.g. "int", "varchar", "datetime"
+	Nullable      bool
+	Default       sql.NullString
+	AutoIncrement bool
+}
+
+// Index describes a MariaDB index that isn't the primary key.
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// ForeignKey describes a MariaDB foreign key constraint.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnUpdate   string
+	OnDelete   string
+}
+
+// Table is a MariaDB table's full definition, read from
+// INFORMATION_SCHEMA.{COLUMNS,KEY_COLUMN_USAGE,STATISTICS,REFERENTIAL_CONSTRAINTS}.
+type Table struct {
+	Name        string
+	Columns     []Column
+	PrimaryKey  []string
+	Indexes     []Index
+	ForeignKeys []ForeignKey
+}
+
+// ReadTable reads table's full definition from MariaDB's INFORMATION_SCHEMA.
+func ReadTable(db *sql.DB, dbName, table string) (*Table, error) {
+	columns, err := readColumns(db, dbName, table)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := readPrimaryKey(db, dbName, table)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := readIndexes(db, dbName, table)
+	if err != nil {
+		return nil, err
+	}
+
+	fks, err := readForeignKeys(db, dbName, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Table{
+		Name:        table,
+		Columns:     columns,
+		PrimaryKey:  pk,
+		Indexes:     indexes,
+		ForeignKeys: fks,
+	}, nil
+}
+
+func readColumns(db *sql.DB, dbName, table string) ([]Column, error) {
+	rows, err := db.Query(`
+		SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, COLUMN_DEFAULT, EXTRA
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY ORDINAL_POSITION
+	`, dbName, table)
+	if err != nil {
+		return nil, fmt.Errorf("querying columns for table %s failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var col Column
+		var isNullable, extra string
+		if err := rows.Scan(&col.Name, &col.Type, &isNullable, &col.Default, &extra); err != nil {
+			return nil, fmt.Errorf("scanning column for table %s failed: %w", table, err)
+		}
+		col.Nullable = isNullable == "YES"
+		col.AutoIncrement = extra == "auto_increment"
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+func readPrimaryKey(db *sql.DB, dbName, table string) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT COLUMN_NAME
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY'
+		ORDER BY ORDINAL_POSITION
+	`, dbName, table)
+	if err != nil {
+		return nil, fmt.Errorf("querying primary key for table %s failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	var pk []string
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, fmt.Errorf("scanning primary key column for table %s failed: %w", table, err)
+		}
+		pk = append(pk, col)
+	}
+	return pk, nil
+}
+
+func readIndexes(db *sql.DB, dbName, table string) ([]Index, error) {
+	rows, err := db.Query(`
+		SELECT INDEX_NAME, COLUMN_NAME, NON_UNIQUE
+		FROM INFORMATION_SCHEMA.STATISTICS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_NAME != 'PRIMARY'
+		ORDER BY INDEX_NAME, SEQ_IN_INDEX
+	`, dbName, table)
+	if err != nil {
+		return nil, fmt.Errorf("querying indexes for table %s failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	byName := make(map[string]*Index)
+	var order []string
+	for rows.Next() {
+		var name, column string
+		var nonUnique int
+		if err := rows.Scan(&name, &column, &nonUnique); err != nil {
+			return nil, fmt.Errorf("scanning index for table %s failed: %w", table, err)
+		}
+		idx, ok := byName[name]
+		if !ok {
+			idx = &Index{Name: name, Unique: nonUnique == 0}
+			byName[name] = idx
+			order = append(order, name)
+		}
+		idx.Columns = append(idx.Columns, column)
+	}
+
+	indexes := make([]Index, 0, len(order))
+	for _, name := range order {
+		indexes = append(indexes, *byName[name])
+	}
+	return indexes, nil
+}
+
+func readForeignKeys(db *sql.DB, dbName, table string) ([]ForeignKey, error) {
+	rows, err := db.Query(`
+		SELECT kcu.CONSTRAINT_NAME, kcu.COLUMN_NAME, kcu.REFERENCED_TABLE_NAME, kcu.REFERENCED_COLUMN_NAME,
+		       rc.UPDATE_RULE, rc.DELETE_RULE
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+		JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+		  ON rc.CONSTRAINT_SCHEMA = kcu.TABLE_SCHEMA AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+		WHERE kcu.TABLE_SCHEMA = ? AND kcu.TABLE_NAME = ? AND kcu.REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION
+	`, dbName, table)
+	if err != nil {
+		return nil, fmt.Errorf("querying foreign keys for table %s failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	byName := make(map[string]*ForeignKey)
+	var order []string
+	for rows.Next() {
+		var name, column, refTable, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return nil, fmt.Errorf("scanning foreign key for table %s failed: %w", table, err)
+		}
+		fk, ok := byName[name]
+		if !ok {
+			fk = &ForeignKey{Name: name, RefTable: refTable, OnUpdate: onUpdate, OnDelete: onDelete}
+			byName[name] = fk
+			order = append(order, name)
+		}
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn)
+	}
+
+	fks := make([]ForeignKey, 0, len(order))
+	for _, name := range order {
+		fks = append(fks, *byName[name])
+	}
+	return fks, nil
+}
@@ -0,0 +1,200 @@
+// Package verify compares a migrated table between its source and
+// destination, to confirm a migration actually landed correctly.
+package verify
+
+import (
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sztanpet/wallabag-mysql-to-sqlite/internal/dialect"
+)
+
+// primaryKeyLookuper mirrors the optional interface in main.go: dialects
+// that can report a table's single-column primary key let verification
+// order rows deterministically and report MIN/MAX.
+type primaryKeyLookuper interface {
+	PrimaryKeyColumn(dbName, table string) (string, error)
+}
+
+// TableResult reports the outcome of comparing one table between source and
+// destination.
+type TableResult struct {
+	Table          string
+	SourceCount    int64
+	TargetCount    int64
+	SourceMin      int64
+	SourceMax      int64
+	TargetMin      int64
+	TargetMax      int64
+	HasPK          bool
+	SourceChecksum string
+	TargetChecksum string
+	Pass           bool
+	Reason         string
+}
+
+// CompareTable re-reads table from src and dst and reports whether they
+// agree on row count, MIN/MAX of the primary key (when there is a
+// single-column one), and a checksum over every column in primary-key
+// order. The checksum is computed in application code, rather than with
+// SQL's MD5(), so it works the same whether or not the dialect has a
+// built-in hash function (SQLite doesn't).
+func CompareTable(src, dst dialect.Dialect, dbName, table string) (TableResult, error) {
+	result := TableResult{Table: table}
+
+	columns, err := src.ColumnInfo(dbName, table)
+	if err != nil {
+		return result, fmt.Errorf("reading column info for table %s failed: %w", table, err)
+	}
+
+	pkColumn, err := primaryKeyColumn(src, dbName, table)
+	if err != nil {
+		return result, err
+	}
+	result.HasPK = pkColumn != ""
+
+	result.SourceCount, err = rowCount(src, table)
+	if err != nil {
+		return result, err
+	}
+	result.TargetCount, err = rowCount(dst, table)
+	if err != nil {
+		return result, err
+	}
+
+	if result.HasPK {
+		result.SourceMin, result.SourceMax, err = minMax(src, table, pkColumn)
+		if err != nil {
+			return result, err
+		}
+		result.TargetMin, result.TargetMax, err = minMax(dst, table, pkColumn)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.SourceChecksum, err = checksum(src, table, columns, pkColumn)
+	if err != nil {
+		return result, err
+	}
+	result.TargetChecksum, err = checksum(dst, table, columns, pkColumn)
+	if err != nil {
+		return result, err
+	}
+
+	switch {
+	case result.SourceCount != result.TargetCount:
+		result.Reason = fmt.Sprintf("row count mismatch: source=%d target=%d", result.SourceCount, result.TargetCount)
+	case result.HasPK && (result.SourceMin != result.TargetMin || result.SourceMax != result.TargetMax):
+		result.Reason = fmt.Sprintf("%s range mismatch: source=[%d,%d] target=[%d,%d]",
+			pkColumn, result.SourceMin, result.SourceMax, result.TargetMin, result.TargetMax)
+	case result.SourceChecksum != result.TargetChecksum:
+		result.Reason = "checksum mismatch"
+	default:
+		result.Pass = true
+	}
+
+	return result, nil
+}
+
+func primaryKeyColumn(src dialect.Dialect, dbName, table string) (string, error) {
+	pkl, ok := src.(primaryKeyLookuper)
+	if !ok {
+		return "", nil
+	}
+	return pkl.PrimaryKeyColumn(dbName, table)
+}
+
+func rowCount(d dialect.Dialect, table string) (int64, error) {
+	var count int64
+	err := d.DB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", d.QuoteIdent(table))).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting rows in table %s failed: %w", table, err)
+	}
+	return count, nil
+}
+
+func minMax(d dialect.Dialect, table, pkColumn string) (min, max int64, err error) {
+	query := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", d.QuoteIdent(pkColumn), d.QuoteIdent(pkColumn), d.QuoteIdent(table))
+	// MIN/MAX are NULL for an empty table; report that as a 0,0 range.
+	var nullMin, nullMax sql.NullInt64
+	err = d.DB().QueryRow(query).Scan(&nullMin, &nullMax)
+	if err != nil {
+		return 0, 0, fmt.Errorf("computing %s range for table %s failed: %w", pkColumn, table, err)
+	}
+	return nullMin.Int64, nullMax.Int64, nil
+}
+
+// checksum computes an MD5 digest over every row of table, ordered by
+// orderCol when there is one (otherwise in whatever order the database
+// returns them, which only gives a meaningful comparison if both sides
+// happen to agree on it).
+func checksum(d dialect.Dialect, table string, columns []dialect.ColumnInfo, orderCol string) (string, error) {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = d.QuoteIdent(col.Name)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(quoted, ", "), d.QuoteIdent(table))
+	if orderCol != "" {
+		query += fmt.Sprintf(" ORDER BY %s", d.QuoteIdent(orderCol))
+	}
+
+	rows, err := d.DB().Query(query)
+	if err != nil {
+		return "", fmt.Errorf("querying table %s for checksum failed: %w", table, err)
+	}
+	defer rows.Close()
+
+	h := md5.New()
+	scanDest := make([]interface{}, len(columns))
+	values := make([]interface{}, len(columns))
+	parts := make([]string, len(columns))
+	for rows.Next() {
+		for i := range columns {
+			scanDest[i] = &values[i]
+		}
+		if err := rows.Scan(scanDest...); err != nil {
+			return "", fmt.Errorf("scanning row of table %s for checksum failed: %w", table, err)
+		}
+		for i, v := range values {
+			parts[i] = checksumLiteral(v)
+		}
+		fmt.Fprintf(h, "%s\n", strings.Join(parts, "|"))
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("iterating table %s for checksum failed: %w", table, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checksumLiteral renders a scanned column value as a stable string for
+// hashing, regardless of which dialect-native Go type it came back as.
+func checksumLiteral(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(x)
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		if x {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return x.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
@@ -0,0 +1,30 @@
+package verify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChecksumLiteral(t *testing.T) {
+	cases := []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{"nil", nil, ""},
+		{"bytes", []byte("hello"), "hello"},
+		{"string", "hello", "hello"},
+		{"int64", int64(42), "42"},
+		{"float64", float64(3.5), "3.5"},
+		{"bool true", true, "1"},
+		{"bool false", false, "0"},
+		{"time", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "2024-01-02T03:04:05Z"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := checksumLiteral(c.in); got != c.want {
+				t.Errorf("checksumLiteral(%#v) = %q, want %q", c.in, got, c.want)
+			}
+		})
+	}
+}
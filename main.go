@@ -1,324 +1,781 @@
 package main
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
-	_ "modernc.org/sqlite"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sztanpet/wallabag-mysql-to-sqlite/internal/config"
+	"github.com/sztanpet/wallabag-mysql-to-sqlite/internal/dialect"
+	"github.com/sztanpet/wallabag-mysql-to-sqlite/internal/schema"
+	"github.com/sztanpet/wallabag-mysql-to-sqlite/internal/verify"
 )
 
-// ColumnInfo holds metadata for a database column
-type ColumnInfo struct {
-	Name string
-	Type string // MariaDB data type string (e.g., "int", "varchar", "datetime")
+// logLevel gates the per-batch progress chatter emitted during a migration;
+// everything else is logged unconditionally. Set from Config.LogLevel at
+// startup.
+var logLevel = "info"
+
+func logDebugf(format string, args ...interface{}) {
+	if logLevel == "debug" {
+		log.Printf(format, args...)
+	}
+}
+
+// migrationLogEntry mirrors a row of the migration_log journaling table.
+type migrationLogEntry struct {
+	ID             string
+	StartedAt      string
+	FinishedAt     sql.NullString
+	SourceRowCount int64
+	TargetRowCount int64
+	LastPKCopied   int64
+	SchemaHash     string
+}
+
+// primaryKeyLookuper is implemented by dialects that can report a table's
+// single-column integer primary key, which is what makes resuming a
+// migration by "WHERE id > ?" possible. Dialects that don't implement it, or
+// that report no usable key (composite or non-integer), always fall back to
+// a full re-copy.
+type primaryKeyLookuper interface {
+	PrimaryKeyColumn(dbName, table string) (string, error)
 }
 
 // main function to orchestrate the migration
 func main() {
-	// --- Configuration ---
-	mariaDBConnStr := "wallabag:wallabag@tcp(127.0.0.1:3306)/wallabag?charset=utf8mb4&parseTime=true" // REPLACE with your MariaDB details
-	sqliteDBPath := "./wallabag.sqlite"                                                               // REPLACE with your SQLite path
-	mariaDBDatabaseName := "wallabag"                                                                 // REPLACE with your MariaDB database name
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	logLevel = cfg.LogLevel
 
 	// --- Database Connections ---
-	log.Println("Connecting to MariaDB...")
-	mariaDB, err := sql.Open("mysql", mariaDBConnStr)
+	log.Printf("Connecting to source (%s)...", cfg.SourceDriver)
+	src, srcDB, err := openDialect(cfg.SourceDriver, cfg.SourceDSN)
 	if err != nil {
-		log.Fatalf("Failed to connect to MariaDB: %v", err)
+		log.Fatalf("Failed to connect to source: %v", err)
 	}
-	defer mariaDB.Close()
+	defer srcDB.Close()
 
-	err = mariaDB.Ping()
-	if err != nil {
-		log.Fatalf("Failed to ping MariaDB: %v", err)
+	if err := srcDB.Ping(); err != nil {
+		log.Fatalf("Failed to ping source: %v", err)
 	}
-	log.Println("Successfully connected to MariaDB.")
+	log.Println("Successfully connected to source.")
 
-	log.Println("Connecting to SQLite...")
-	sqliteDB, err := sql.Open("sqlite", sqliteDBPath) // 'sqlite3' is the driver name for modernc.org/sqlite
+	log.Printf("Connecting to target (%s)...", cfg.TargetDriver)
+	dst, targetDB, err := openDialect(cfg.TargetDriver, cfg.TargetDSN)
 	if err != nil {
-		log.Fatalf("Failed to connect to SQLite: %v", err)
+		log.Fatalf("Failed to connect to target: %v", err)
+	}
+	defer targetDB.Close()
+
+	if err := targetDB.Ping(); err != nil {
+		log.Fatalf("Failed to ping target: %v", err)
 	}
-	defer sqliteDB.Close()
+	log.Println("Successfully connected to target.")
 
-	err = sqliteDB.Ping()
+	// Get list of tables from the source, narrowed to --tables/--exclude-tables.
+	tables, err := src.ListTables(cfg.SourceDB)
 	if err != nil {
-		log.Fatalf("Failed to ping SQLite: %v", err)
+		log.Fatalf("Failed to get tables from source: %v", err)
+	}
+	tables = config.FilterTables(tables, cfg.Tables, cfg.ExcludeTables)
+
+	if cfg.Verify {
+		runVerify(cfg.SourceDriver, cfg.SourceDSN, cfg.TargetDriver, cfg.TargetDSN, cfg.SourceDB, tables)
+		return
+	}
+
+	if cfg.DryRun {
+		runDryRun(src, dst, cfg.SourceDB, tables)
+		return
 	}
-	log.Println("Successfully connected to SQLite.")
 
 	// --- Migration Process ---
 
-	// Disable foreign key checks for faster and smoother import
-	_, err = sqliteDB.Exec("PRAGMA foreign_keys = OFF;")
+	if sqliteDst, ok := dst.(*dialect.SQLite); ok {
+		if _, err := sqliteDst.DB().Exec("PRAGMA foreign_keys = OFF;"); err != nil {
+			log.Fatalf("Failed to disable SQLite foreign keys: %v", err)
+		}
+		log.Println("SQLite foreign key checks disabled for import.")
+
+		if err := sqliteDst.ApplyImportPragmas(); err != nil {
+			log.Fatalf("Failed to tune destination connection for import: %v", err)
+		}
+	}
+
+	// Ensure the journaling table exists so migrations can be resumed.
+	if err := ensureMigrationLogTable(dst); err != nil {
+		log.Fatalf("Failed to set up migration_log table: %v", err)
+	}
+
+	if cfg.CreateSchema {
+		if cfg.SourceDriver != "mysql" {
+			log.Fatalf("--create-schema requires --source-driver=mysql: schema translation reads MariaDB's INFORMATION_SCHEMA, got %q", cfg.SourceDriver)
+		}
+		for _, tableName := range tables {
+			log.Printf("Creating schema for table '%s'...", tableName)
+			if err := schema.CreateTable(srcDB, targetDB, dst.Name(), cfg.SourceDB, tableName); err != nil {
+				log.Fatalf("Error creating schema for table '%s': %v", tableName, err)
+			}
+		}
+	}
+
+	fkOff := !cfg.FK
+	if cfg.SourceDriver != "mysql" {
+		if cfg.FK {
+			log.Printf("Foreign-key dependency ordering requires --source-driver=mysql; migrating tables in source order.")
+		}
+		fkOff = true
+	}
+	orderedTables, deps, err := topologicalTableOrder(srcDB, cfg.SourceDB, tables, fkOff)
+	if err != nil {
+		log.Fatalf("Failed to determine table migration order: %v", err)
+	}
+
+	if cfg.Parallel > 1 {
+		newDst := func() (dialect.Dialect, error) {
+			return openTargetDialectForWorker(cfg.TargetDriver, cfg.TargetDSN)
+		}
+		if err := migrateTablesParallel(src, newDst, cfg.SourceDB, orderedTables, deps, cfg.Parallel, cfg.BatchSize); err != nil {
+			log.Fatalf("Error migrating tables: %v", err)
+		}
+	} else {
+		for _, tableName := range orderedTables {
+			log.Printf("Migrating table '%s'...", tableName)
+			if err := migrateTable(src, dst, cfg.SourceDB, tableName, cfg.BatchSize); err != nil {
+				log.Fatalf("Error migrating table '%s': %v", tableName, err)
+			}
+			log.Printf("Successfully migrated table '%s'.", tableName)
+		}
+	}
+
+	if sqliteDst, ok := dst.(*dialect.SQLite); ok {
+		if _, err := sqliteDst.DB().Exec("PRAGMA foreign_keys = ON;"); err != nil {
+			log.Fatalf("Failed to re-enable SQLite foreign keys: %v", err)
+		}
+		log.Println("SQLite foreign key checks re-enabled.")
+	}
+
+	log.Println("Migration complete!")
+}
+
+// openDialect opens a connection to the database described by driverName
+// ("sqlite", "mysql", or "postgres") and dsn, and wraps it in the matching
+// dialect.Dialect implementation. It is used for both the source and the
+// destination side of a migration, so any pairing of supported backends is
+// reachable.
+func openDialect(driverName, dsn string) (dialect.Dialect, *sql.DB, error) {
+	switch driverName {
+	case "sqlite":
+		db, err := sql.Open("sqlite", dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dialect.NewSQLite(db), db, nil
+	case "mysql":
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dialect.NewMySQL(db), db, nil
+	case "postgres":
+		db, err := sql.Open("pgx", dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dialect.NewPostgres(db), db, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown driver %q (must be sqlite, mysql, or postgres)", driverName)
+	}
+}
+
+// openTargetDialectForWorker opens an additional destination connection for
+// a parallel migration worker, applying SQLite's import-tuning pragmas when
+// the target is SQLite.
+func openTargetDialectForWorker(driverName, dsn string) (dialect.Dialect, error) {
+	dst, db, err := openDialect(driverName, dsn)
 	if err != nil {
-		log.Fatalf("Failed to disable SQLite foreign keys: %v", err)
+		return nil, err
+	}
+	if sd, ok := dst.(*dialect.SQLite); ok {
+		if err := sd.ApplyImportPragmas(); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// runDryRun walks tables and prints the row count that would be copied and
+// the INSERT template that would be used, without opening a write
+// transaction or touching the destination's data.
+func runDryRun(src, dst dialect.Dialect, dbName string, tables []string) {
+	for _, tableName := range tables {
+		columns, err := src.ColumnInfo(dbName, tableName)
+		if err != nil {
+			log.Fatalf("Failed to get column info for table '%s': %v", tableName, err)
+		}
+
+		count, err := sourceRowCount(src, tableName)
+		if err != nil {
+			log.Fatalf("Failed to count rows in table '%s': %v", tableName, err)
+		}
+
+		columnNames := make([]string, len(columns))
+		for i, col := range columns {
+			columnNames[i] = col.Name
+		}
+
+		pkColumn, err := singleColumnPrimaryKey(src, dbName, tableName)
+		if err != nil {
+			log.Fatalf("Failed to determine primary key for table '%s': %v", tableName, err)
+		}
+		var pkColumns []string
+		if pkColumn != "" {
+			pkColumns = []string{pkColumn}
+		}
+
+		log.Printf("[dry-run] table '%s': %d row(s) would be copied", tableName, count)
+		log.Printf("[dry-run] table '%s': insert template: %s", tableName, dst.RenderInsert(tableName, columnNames, 1, pkColumns))
 	}
-	log.Println("SQLite foreign key checks disabled for import.")
+}
 
-	// Get list of tables from MariaDB
-	tables, err := getMariaDBTables(mariaDB, mariaDBDatabaseName)
+// runVerify re-opens both databases read-only (where the driver supports it)
+// and compares every table between them, logging a PASS/FAIL line per table
+// and exiting the process with a non-zero status if any table fails to
+// verify.
+func runVerify(sourceDriver, sourceDSN, targetDriver, targetDSN, dbName string, tables []string) {
+	srcVerifyDSN := sourceDSN
+	if sourceDriver == "sqlite" {
+		srcVerifyDSN = fmt.Sprintf("file:%s?mode=ro", sourceDSN)
+	}
+	src, srcDB, err := openDialect(sourceDriver, srcVerifyDSN)
 	if err != nil {
-		log.Fatalf("Failed to get tables from MariaDB: %v", err)
+		log.Fatalf("Failed to open source for verification: %v", err)
 	}
+	defer srcDB.Close()
 
-	// Use a map for quick lookup and to track migrated status
-	tablesToMigrate := make(map[string]bool)
-	for _, table := range tables {
-		tablesToMigrate[table] = false // Not yet migrated
+	targetVerifyDSN := targetDSN
+	if targetDriver == "sqlite" {
+		targetVerifyDSN = fmt.Sprintf("file:%s?mode=ro", targetDSN)
+	}
+	dst, targetDB, err := openDialect(targetDriver, targetVerifyDSN)
+	if err != nil {
+		log.Fatalf("Failed to open target for verification: %v", err)
 	}
+	defer targetDB.Close()
 
+	failures := 0
 	for _, tableName := range tables {
-		if tablesToMigrate[tableName] { // Already migrated
+		result, err := verify.CompareTable(src, dst, dbName, tableName)
+		if err != nil {
+			log.Fatalf("Failed to verify table '%s': %v", tableName, err)
+		}
+		if result.Pass {
+			log.Printf("[verify] PASS table '%s' (%d rows)", tableName, result.SourceCount)
 			continue
 		}
+		failures++
+		log.Printf("[verify] FAIL table '%s': %s", tableName, result.Reason)
+	}
+
+	if failures > 0 {
+		log.Printf("Verification failed for %d of %d table(s).", failures, len(tables))
+		os.Exit(1)
+	}
+	log.Println("Verification passed for all tables.")
+}
 
-		log.Printf("Migrating table '%s'...", tableName)
-		err = migrateTable(mariaDB, sqliteDB, mariaDBDatabaseName, tableName)
+// topologicalTableOrder returns tables ordered so that a table referenced by
+// another table's foreign key always comes before it, along with a
+// dependency map from table name to the tables it depends on (its FK
+// targets). If fkOff is true, ordering is skipped and tables is returned
+// unchanged with an empty dependency map.
+func topologicalTableOrder(mariaDB *sql.DB, dbName string, tables []string, fkOff bool) ([]string, map[string][]string, error) {
+	deps := make(map[string][]string, len(tables))
+	if fkOff {
+		return tables, deps, nil
+	}
+
+	known := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		known[t] = true
+	}
+	for _, t := range tables {
+		tbl, err := schema.ReadTable(mariaDB, dbName, t)
 		if err != nil {
-			log.Fatalf("Error migrating table '%s': %v", tableName, err)
+			return nil, nil, fmt.Errorf("reading foreign keys for table %s failed: %w", t, err)
+		}
+		for _, fk := range tbl.ForeignKeys {
+			if known[fk.RefTable] && fk.RefTable != t {
+				deps[t] = append(deps[t], fk.RefTable)
+			}
 		}
-		log.Printf("Successfully migrated table '%s'.", tableName)
-		tablesToMigrate[tableName] = true
 	}
 
-	// Re-enable foreign key checks
-	_, err = sqliteDB.Exec("PRAGMA foreign_keys = ON;")
+	order, err := orderFromDeps(tables, deps)
 	if err != nil {
-		log.Fatalf("Failed to re-enable SQLite foreign keys: %v", err)
+		return nil, nil, err
+	}
+	return order, deps, nil
+}
+
+// orderFromDeps topologically sorts tables so that every table appears
+// after all of the tables it depends on (per deps), using a depth-first
+// visit with cycle detection. It is split out from topologicalTableOrder so
+// the sort itself can be tested without a database connection.
+func orderFromDeps(tables []string, deps map[string][]string) ([]string, error) {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(tables))
+	var order []string
+	var visit func(string) error
+	visit = func(t string) error {
+		switch state[t] {
+		case done:
+			return nil
+		case inProgress:
+			return fmt.Errorf("circular foreign key dependency detected at table %s", t)
+		}
+		state[t] = inProgress
+		for _, dep := range deps[t] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[t] = done
+		order = append(order, t)
+		return nil
 	}
-	log.Println("SQLite foreign key checks re-enabled.")
 
-	log.Println("Migration complete!")
+	for _, t := range tables {
+		if err := visit(t); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// migrateTablesParallel migrates tables concurrently, up to parallel at a
+// time, opening a fresh destination connection per table via newDst (so
+// concurrent writers don't contend over a single shared connection) and
+// honoring deps so a table only starts once every table it depends on (via
+// foreign keys) has finished.
+func migrateTablesParallel(src dialect.Dialect, newDst func() (dialect.Dialect, error), dbName string, tables []string, deps map[string][]string, parallel, batchSize int) error {
+	done := make(map[string]chan struct{}, len(tables))
+	for _, t := range tables {
+		done[t] = make(chan struct{})
+	}
+
+	g := new(errgroup.Group)
+	g.SetLimit(parallel)
+
+	for _, tableName := range tables {
+		tableName := tableName
+		g.Go(func() error {
+			for _, dep := range deps[tableName] {
+				<-done[dep]
+			}
+			defer close(done[tableName])
+
+			dst, err := newDst()
+			if err != nil {
+				return fmt.Errorf("opening destination connection for table %s failed: %w", tableName, err)
+			}
+			defer dst.DB().Close()
+
+			log.Printf("Migrating table '%s'...", tableName)
+			if err := migrateTable(src, dst, dbName, tableName, batchSize); err != nil {
+				return fmt.Errorf("error migrating table '%s': %w", tableName, err)
+			}
+			log.Printf("Successfully migrated table '%s'.", tableName)
+			return nil
+		})
+	}
+
+	return g.Wait()
 }
 
-// getMariaDBTables retrieves a list of table names from the given MariaDB database
-func getMariaDBTables(db *sql.DB, dbName string) ([]string, error) {
-	rows, err := db.Query("SELECT table_name FROM information_schema.tables WHERE table_schema = ?", dbName)
+// ensureMigrationLogTable creates the migration_log journaling table, used to
+// record the state of every table migration so a crashed or interrupted run
+// can resume instead of re-copying data that already landed. The schema is
+// deliberately kept to portable types so it works unchanged across dst
+// dialects. id is VARCHAR(255) rather than TEXT because MySQL rejects a bare
+// TEXT/BLOB column in a key specification ("without a key length").
+func ensureMigrationLogTable(dst dialect.Dialect) error {
+	_, err := dst.DB().Exec(`
+		CREATE TABLE IF NOT EXISTS migration_log (
+			id               VARCHAR(255) PRIMARY KEY,
+			source_table     TEXT NOT NULL,
+			schema_hash      TEXT NOT NULL,
+			started_at       TEXT NOT NULL,
+			finished_at      TEXT,
+			source_row_count INTEGER NOT NULL DEFAULT 0,
+			target_row_count INTEGER NOT NULL DEFAULT 0,
+			last_pk_copied   INTEGER NOT NULL DEFAULT 0
+		)
+	`)
 	if err != nil {
-		return nil, fmt.Errorf("querying tables failed: %w", err)
+		return fmt.Errorf("creating migration_log table failed: %w", err)
 	}
-	defer rows.Close()
+	return nil
+}
 
-	var tables []string
-	var tableName string
-	for rows.Next() {
-		if err := rows.Scan(&tableName); err != nil {
-			return nil, fmt.Errorf("scanning table name failed: %w", err)
-		}
-		tables = append(tables, tableName)
+// migrationID builds a stable identifier for a table migration, so that it
+// can be looked up across runs: <db>.<table>.<schema_hash>.
+func migrationID(dbName, tableName, schemaHash string) string {
+	return fmt.Sprintf("%s.%s.%s", dbName, tableName, schemaHash)
+}
+
+// columnSchemaHash returns a stable hash of a table's column names and types,
+// used to detect that the source schema changed since a previous migration,
+// which invalidates any checkpoint recorded for it.
+func columnSchemaHash(columns []dialect.ColumnInfo) string {
+	h := sha256.New()
+	for _, col := range columns {
+		fmt.Fprintf(h, "%s:%s;", col.Name, col.Type)
 	}
-	return tables, nil
+	return hex.EncodeToString(h.Sum(nil))
 }
 
-// getMariaDBColumnInfo retrieves column names and types for a given table
-func getMariaDBColumnInfo(db *sql.DB, dbName, tableName string) ([]ColumnInfo, error) {
-	rows, err := db.Query(`
-        SELECT COLUMN_NAME, DATA_TYPE
-        FROM INFORMATION_SCHEMA.COLUMNS
-        WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
-        ORDER BY ORDINAL_POSITION
-    `, dbName, tableName)
+// loadMigrationLog fetches the recorded state for a migration id, returning a
+// nil entry (and no error) if nothing has been recorded for it yet.
+func loadMigrationLog(dst dialect.Dialect, id string) (*migrationLogEntry, error) {
+	row := dst.DB().QueryRow(fmt.Sprintf(`
+		SELECT id, started_at, finished_at, source_row_count, target_row_count, last_pk_copied, schema_hash
+		FROM migration_log WHERE id = %s
+	`, dst.Placeholder(0)), id)
+
+	var entry migrationLogEntry
+	err := row.Scan(&entry.ID, &entry.StartedAt, &entry.FinishedAt, &entry.SourceRowCount, &entry.TargetRowCount, &entry.LastPKCopied, &entry.SchemaHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("querying column info for table %s failed: %w", tableName, err)
+		return nil, fmt.Errorf("loading migration_log entry %s failed: %w", id, err)
 	}
-	defer rows.Close()
+	return &entry, nil
+}
 
-	var columns []ColumnInfo
-	var colName, colType string
-	for rows.Next() {
-		if err := rows.Scan(&colName, &colType); err != nil {
-			return nil, fmt.Errorf("scanning column info for table %s failed: %w", tableName, err)
-		}
-		columns = append(columns, ColumnInfo{Name: colName, Type: colType})
+// singleColumnPrimaryKey returns the name of tableName's primary key column
+// in the source database, if the source dialect can report one, it has
+// exactly one, and it is an integer type. An empty string means resuming by
+// primary key is not possible and the table must be re-copied in full on
+// every run.
+func singleColumnPrimaryKey(src dialect.Dialect, dbName, tableName string) (string, error) {
+	pkl, ok := src.(primaryKeyLookuper)
+	if !ok {
+		return "", nil
 	}
-	return columns, nil
+	return pkl.PrimaryKeyColumn(dbName, tableName)
 }
 
-// migrateTable performs the generic migration for a single table
-func migrateTable(mariaDB *sql.DB, sqliteDB *sql.DB, mariaDBDatabaseName, tableName string) error {
-	truncateQuery := fmt.Sprintf("DELETE FROM %s", tableName)
+// sourceRowCount returns the current row count of tableName in the source
+// database, used to detect whether it changed since a previous migration
+// finished.
+func sourceRowCount(src dialect.Dialect, tableName string) (int64, error) {
+	var count int64
+	err := src.DB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", src.QuoteIdent(tableName))).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting rows in table %s failed: %w", tableName, err)
+	}
+	return count, nil
+}
 
+// migrateTable performs the generic migration for a single table from src to
+// dst, resuming from a prior run's checkpoint in migration_log when
+// possible, and skipping the table entirely if it was already fully migrated
+// and the source is unchanged.
+func migrateTable(src, dst dialect.Dialect, dbName, tableName string, batchSize int) error {
 	// Get column information to build dynamic queries and handle types
-	columns, err := getMariaDBColumnInfo(mariaDB, mariaDBDatabaseName, tableName)
+	columns, err := src.ColumnInfo(dbName, tableName)
 	if err != nil {
 		return fmt.Errorf("could not get column info for %s: %w", tableName, err)
 	}
 
+	hash := columnSchemaHash(columns)
+	id := migrationID(dbName, tableName, hash)
+
+	srcRowCount, err := sourceRowCount(src, tableName)
+	if err != nil {
+		return err
+	}
+
+	entry, err := loadMigrationLog(dst, id)
+	if err != nil {
+		return err
+	}
+	if entry != nil && entry.FinishedAt.Valid && entry.SourceRowCount == srcRowCount {
+		log.Printf("Skipping table '%s': already migrated (%d rows, unchanged schema).", tableName, srcRowCount)
+		return nil
+	}
+
+	pkColumn, err := singleColumnPrimaryKey(src, dbName, tableName)
+	if err != nil {
+		return err
+	}
+
+	resuming := entry != nil && !entry.FinishedAt.Valid && entry.LastPKCopied > 0 && pkColumn != ""
+	var lastPK int64
+	if resuming {
+		lastPK = entry.LastPKCopied
+		log.Printf("Resuming table '%s' from %s > %d.", tableName, pkColumn, lastPK)
+	}
+
+	startedAt := time.Now().UTC().Format(time.RFC3339)
+	migrationLogColumns := []string{"id", "source_table", "schema_hash", "started_at", "finished_at", "source_row_count", "target_row_count", "last_pk_copied"}
+	insertLogQuery := dst.RenderInsert("migration_log", migrationLogColumns, 1, []string{"id"})
+	if _, err := dst.DB().Exec(insertLogQuery, id, tableName, hash, startedAt, nil, srcRowCount, 0, lastPK); err != nil {
+		return fmt.Errorf("recording migration_log start for %s failed: %w", tableName, err)
+	}
+
 	// Build SELECT query string
 	columnNames := make([]string, len(columns))
+	quotedColumns := make([]string, len(columns))
 	for i, col := range columns {
 		columnNames[i] = col.Name
+		quotedColumns[i] = src.QuoteIdent(col.Name)
 	}
-	selectQuery := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columnNames, ", "), tableName)
-
-	// Build INSERT query string
-	placeholders := make([]string, len(columns))
-	for i := range columns {
-		placeholders[i] = "?"
+	selectQuery := fmt.Sprintf("SELECT %s FROM %s", strings.Join(quotedColumns, ", "), src.QuoteIdent(tableName))
+	if resuming {
+		selectQuery += fmt.Sprintf(" WHERE %s > %d", src.QuoteIdent(pkColumn), lastPK)
+	}
+	if pkColumn != "" {
+		selectQuery += fmt.Sprintf(" ORDER BY %s", src.QuoteIdent(pkColumn))
 	}
-	insertQuery := fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (%s)",
-		tableName, strings.Join(columnNames, ", "), strings.Join(placeholders, ", "))
 
-	// Begin a transaction for SQLite
-	tx, err := sqliteDB.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin SQLite transaction for %s: %w", tableName, err)
+	// insertChunkRows is how many value tuples fit in a single multi-row
+	// INSERT without exceeding the destination driver's bind parameter limit.
+	insertChunkRows := dst.MaxBatchParams() / len(columns)
+	if insertChunkRows < 1 {
+		insertChunkRows = 1
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-			panic(r)
-		} else if err != nil {
-			tx.Rollback()
-		} else {
-			err = tx.Commit()
+
+	pkIndex := -1
+	if pkColumn != "" {
+		for i, name := range columnNames {
+			if name == pkColumn {
+				pkIndex = i
+				break
+			}
 		}
-	}()
+	}
 
-	_, err = tx.Exec(truncateQuery)
+	// Query data from the source
+	rows, err := src.DB().Query(selectQuery)
 	if err != nil {
-		return fmt.Errorf("failed to truncate table %s: %w", tableName, err)
+		return fmt.Errorf("failed to query source table %s: %w", tableName, err)
 	}
+	defer rows.Close()
 
-	// Prepare the SQLite INSERT statement
-	stmt, err := tx.Prepare(insertQuery)
+	tx, err := dst.DB().Begin()
 	if err != nil {
-		return fmt.Errorf("failed to prepare SQLite insert statement for %s: %w", tableName, err)
+		return fmt.Errorf("failed to begin destination transaction for %s: %w", tableName, err)
 	}
-	defer stmt.Close()
 
-	// Query data from MariaDB
-	rows, err := mariaDB.Query(selectQuery)
-	if err != nil {
-		return fmt.Errorf("failed to query MariaDB table %s: %w", tableName, err)
+	if !resuming {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", dst.QuoteIdent(tableName))); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to truncate table %s: %w", tableName, err)
+		}
 	}
-	defer rows.Close()
 
-	// Prepare slices for scanning and arguments
+	// Prepare a slice for scanning and a buffer of pending, not-yet-flushed
+	// insert arguments, flattened row after row.
 	scanDest := make([]interface{}, len(columns))
-	colValues := make([]interface{}, len(columns))
+	pending := make([]interface{}, 0, insertChunkRows*len(columns))
+	pendingRows := 0
+
+	var pkColumns []string
+	if pkColumn != "" {
+		pkColumns = []string{pkColumn}
+	}
+
+	flushInsert := func() error {
+		if pendingRows == 0 {
+			return nil
+		}
+		insertQuery := dst.RenderInsert(tableName, columnNames, pendingRows, pkColumns)
+		if _, err := tx.Exec(insertQuery, pending...); err != nil {
+			return fmt.Errorf("failed to insert %d record(s) into table %s: %w", pendingRows, tableName, err)
+		}
+		pending = pending[:0]
+		pendingRows = 0
+		return nil
+	}
 
 	recordCount := 0
+	inBatch := 0
 	for rows.Next() {
 		recordCount++
+		inBatch++
 
-		// Initialize scan destinations dynamically based on MariaDB column types
+		// Initialize scan destinations dynamically based on source column types
 		for i, col := range columns {
-			colValues[i], err = mapMariaDBTypeToGoType(col.Type)
-			if err != nil {
-				return fmt.Errorf("unsupported MariaDB type %s for column %s in table %s: %w", col.Type, col.Name, tableName, err)
-			}
-			scanDest[i] = &colValues[i]
+			scanDest[i] = dialect.NewScanDest(src.MapColumnType(col))
 		}
 
-		// Scan data from MariaDB row
+		// Scan data from the source row
 		if err := rows.Scan(scanDest...); err != nil {
-			return fmt.Errorf("failed to scan MariaDB row in table %s (record %d): %w", tableName, recordCount, err)
+			tx.Rollback()
+			return fmt.Errorf("failed to scan source row in table %s (record %d): %w", tableName, recordCount, err)
 		}
 
-		// Prepare arguments for SQLite insert, converting types as needed
-		insertArgs := make([]interface{}, len(columns))
+		// Buffer converted values for the destination insert
 		for i, col := range columns {
-			insertArgs[i] = convertToGoToSQLite(colValues[i], col.Type)
+			v := convertGoValue(scanDest[i], col, dst.Name())
+			pending = append(pending, v)
+			if i == pkIndex {
+				if pk, ok := v.(int64); ok {
+					lastPK = pk
+				}
+			}
 		}
+		pendingRows++
 
-		// Execute INSERT into SQLite
-		if _, err := stmt.Exec(insertArgs...); err != nil {
-			return fmt.Errorf("failed to insert record %d into SQLite table %s: %w", recordCount, tableName, err)
+		if pendingRows >= insertChunkRows {
+			if err := flushInsert(); err != nil {
+				tx.Rollback()
+				return err
+			}
 		}
 
-		if recordCount%1000 == 0 {
-			log.Printf("Migrated %d records in table '%s'...", recordCount, tableName)
+		if inBatch >= batchSize {
+			if err := flushInsert(); err != nil {
+				tx.Rollback()
+				return err
+			}
+			if _, err := tx.Exec(fmt.Sprintf(
+				"UPDATE migration_log SET last_pk_copied = %s, target_row_count = target_row_count + %s WHERE id = %s",
+				dst.Placeholder(0), dst.Placeholder(1), dst.Placeholder(2)),
+				lastPK, inBatch, id); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to checkpoint migration_log for %s: %w", tableName, err)
+			}
+			if err := tx.Commit(); err != nil {
+				return fmt.Errorf("failed to commit batch for table %s: %w", tableName, err)
+			}
+
+			tx, err = dst.DB().Begin()
+			if err != nil {
+				return fmt.Errorf("failed to begin destination transaction for %s: %w", tableName, err)
+			}
+
+			logDebugf("Migrated %d records in table '%s'...", recordCount, tableName)
+			inBatch = 0
 		}
 	}
 
 	if err := rows.Err(); err != nil {
+		tx.Rollback()
 		return fmt.Errorf("error during row iteration for table %s: %w", tableName, err)
 	}
 
-	log.Printf("Finished migrating %d records in table '%s'.", recordCount, tableName)
-	return nil
-}
+	if err := flushInsert(); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-// mapMariaDBTypeToGoType maps a MariaDB data type string to an appropriate Go type for scanning
-// This is crucial for handling NULL values and preparing for type conversions.
-func mapMariaDBTypeToGoType(mariaDBType string) (interface{}, error) {
-	switch strings.ToLower(mariaDBType) {
-	case "int", "tinyint", "smallint", "mediumint", "bigint":
-		return sql.NullInt64{}, nil
-	case "float", "double", "decimal", "numeric":
-		return sql.NullFloat64{}, nil
-	case "varchar", "text", "tinytext", "mediumtext", "longtext", "char", "json":
-		return sql.NullString{}, nil // JSON will be read as strings/bytes
-	case "blob", "longblob", "mediumblob", "tinyblob":
-		// BLOBs are read as []byte. SQLite also supports BLOB type.
-		// If you intend to convert them to text (e.g., base64), you'd handle it in convertToGoToSQLite
-		return []byte{}, nil
-	case "datetime", "timestamp", "date":
-		return sql.NullTime{}, nil
-	case "boolean": // MariaDB's BOOLEAN is a TINYINT(1)
-		return sql.NullBool{}, nil
-	// Add more types as needed based on your MariaDB schema
-	default:
-		// Fallback for unknown types to string, or return an error if strict
-		log.Printf("Warning: Unhandled MariaDB type '%s'. Attempting to scan as string.", mariaDBType)
-		return sql.NullString{}, nil // Default to string for unknown types
+	finishedAt := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec(fmt.Sprintf(
+		"UPDATE migration_log SET finished_at = %s, last_pk_copied = %s, target_row_count = target_row_count + %s WHERE id = %s",
+		dst.Placeholder(0), dst.Placeholder(1), dst.Placeholder(2), dst.Placeholder(3)),
+		finishedAt, lastPK, inBatch, id); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to finalize migration_log for %s: %w", tableName, err)
 	}
-}
 
-// convertToGoToSQLite performs final type conversion from Go's sql.NullX types to SQLite's TEXT/INTEGER/REAL
-// Newlines are NOT stripped here as per user's request.
-func convertToGoToSQLite(val interface{}, mariaDBType string) interface{} {
-	if val == nil {
-		return nil // Handle database NULLs
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit final batch for table %s: %w", tableName, err)
 	}
 
-	lowerMariaDBType := strings.ToLower(mariaDBType)
+	log.Printf("Finished migrating %d records in table '%s'.", recordCount, tableName)
+	return nil
+}
 
-	switch v := val.(type) {
-	case int64:
-		return v
-	case float64:
-		return v
-	case bool:
-		if v {
+// convertGoValue performs final type conversion from the Go value scanned
+// out of the source into a value suitable for the destination dialect.
+// Newlines inside a string value are kept; only leading and trailing
+// whitespace (newlines included) is trimmed.
+// convertGoValue converts dest, one of the typed scan destinations allocated
+// by dialect.NewScanDest, into a value suitable for binding into the
+// destination insert. dest's concrete type is determined by col's ScanKind,
+// so the switch below mirrors dialect.NewScanDest's cases exactly.
+func convertGoValue(dest interface{}, col dialect.ColumnInfo, dstDialectName string) interface{} {
+	lowerType := strings.ToLower(col.Type)
+
+	switch v := dest.(type) {
+	case *sql.NullInt64:
+		if !v.Valid {
+			return nil
+		}
+		return v.Int64
+	case *sql.NullFloat64:
+		if !v.Valid {
+			return nil
+		}
+		return v.Float64
+	case *sql.NullBool:
+		if !v.Valid {
+			return nil
+		}
+		if dstDialectName == "postgres" {
+			return v.Bool // Postgres has a native boolean type
+		}
+		if v.Bool {
 			return 1
-		} // Convert Go bool to SQLite INTEGER (0 or 1)
+		} // Convert Go bool to an INTEGER (0 or 1) for MySQL/SQLite
 		return 0
-	case time.Time:
-		// Convert time to ISO 8601 string for SQLite TEXT column
-		return v.Format(time.RFC3339)
-	case string:
-		// This case handles data that was already scanned as a Go string.
+	case *sql.NullTime:
+		if !v.Valid {
+			return nil
+		}
+		// Convert time to ISO 8601 string for TEXT-affinity destination columns
+		return v.Time.Format(time.RFC3339)
+	case *sql.NullString:
+		if !v.Valid {
+			return nil
+		}
 		// Apply string cleaning: TRIM whitespace and remove null bytes.
-		cleanedString := strings.TrimSpace(v)
+		cleanedString := strings.TrimSpace(v.String)
 		cleanedString = strings.ReplaceAll(cleanedString, string(rune(0)), "") // Remove NULL bytes (CHAR(0))
 		return cleanedString
-	case []byte:
-		// This case is crucial for data that the MariaDB driver returned as raw bytes.
-		// This can happen for VARCHAR, TEXT, JSON, and BLOB columns.
-		// We need to differentiate based on the *original MariaDB type* to know how to treat it.
-		if strings.Contains(lowerMariaDBType, "text") || strings.Contains(lowerMariaDBType, "char") || strings.Contains(lowerMariaDBType, "json") || strings.Contains(lowerMariaDBType, "varchar") {
+	case *[]byte:
+		if *v == nil {
+			return nil
+		}
+		// This case is crucial for data that the source driver returned as raw
+		// bytes. This can happen for VARCHAR, TEXT, JSON, and BLOB columns.
+		// We need to differentiate based on the *original source type* to know
+		// how to treat it.
+		if strings.Contains(lowerType, "text") || strings.Contains(lowerType, "char") || strings.Contains(lowerType, "json") || strings.Contains(lowerType, "varchar") {
 			// It's a text-like column, convert []byte to string and then clean it.
-			cleanedString := string(v)
+			cleanedString := string(*v)
 			cleanedString = strings.TrimSpace(cleanedString)
 			cleanedString = strings.ReplaceAll(cleanedString, string(rune(0)), "") // Remove NULL bytes
 			return cleanedString
-		} else if strings.Contains(lowerMariaDBType, "blob") {
-			// It's a true BLOB, return as is. SQLite handles []byte as BLOB.
-			return v
+		} else if strings.Contains(lowerType, "blob") || strings.Contains(lowerType, "bytea") {
+			// It's a true BLOB, return as is.
+			return *v
 		}
 		// Fallback for unexpected []byte if it's not a known text/blob type, treat as raw BLOB.
-		log.Printf("Warning: Unexpected []byte for MariaDB type '%s'. Treating as raw BLOB.", mariaDBType)
-		return v
+		log.Printf("Warning: Unexpected []byte for column type '%s'. Treating as raw BLOB.", col.Type)
+		return *v
 	default:
-		// If we reach here, it's an unhandled Go type after scanning.
-		log.Printf("Warning: Unhandled Go type '%T' for MariaDB type '%s'. Inserting as is.", v, mariaDBType)
+		// If we reach here, it's an unhandled scan destination type.
+		log.Printf("Warning: Unhandled scan destination type '%T' for column type '%s'. Inserting as is.", v, col.Type)
 		return v
 	}
 }
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/sztanpet/wallabag-mysql-to-sqlite/internal/dialect"
+)
+
+// newMemorySQLite opens a private, single-connection in-memory SQLite
+// database wrapped as a dialect.Dialect. SetMaxOpenConns(1) keeps every
+// query on the same connection, since each new connection to ":memory:"
+// would otherwise see its own empty database.
+func newMemorySQLite(t *testing.T) dialect.Dialect {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("opening in-memory sqlite failed: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return dialect.NewSQLite(db)
+}
+
+func TestOrderFromDepsOrdersDependenciesFirst(t *testing.T) {
+	tables := []string{"entry", "annotation", "tag"}
+	deps := map[string][]string{
+		"entry":      {"tag"},
+		"annotation": {"entry"},
+	}
+
+	order, err := orderFromDeps(tables, deps)
+	if err != nil {
+		t.Fatalf("orderFromDeps returned error: %v", err)
+	}
+
+	pos := make(map[string]int, len(order))
+	for i, t := range order {
+		pos[t] = i
+	}
+	if pos["tag"] > pos["entry"] {
+		t.Errorf("expected 'tag' before 'entry', got order %v", order)
+	}
+	if pos["entry"] > pos["annotation"] {
+		t.Errorf("expected 'entry' before 'annotation', got order %v", order)
+	}
+	if !reflect.DeepEqual(sortedCopy(order), sortedCopy(tables)) {
+		t.Errorf("orderFromDeps changed the table set: got %v, want a permutation of %v", order, tables)
+	}
+}
+
+func TestOrderFromDepsDetectsCycle(t *testing.T) {
+	deps := map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"a"},
+	}
+
+	_, err := orderFromDeps([]string{"a", "b", "c"}, deps)
+	if err == nil {
+		t.Fatal("expected an error for a circular dependency, got nil")
+	}
+}
+
+// TestMigrateTableCopiesRowsAndSkipsUnchangedRerun exercises migrateTable end
+// to end against real sqlite://:memory: connections: a first run must copy
+// every row and record a finished migration_log entry, and a second run
+// against the same unchanged source must skip the copy entirely instead of
+// re-inserting anything.
+func TestMigrateTableCopiesRowsAndSkipsUnchangedRerun(t *testing.T) {
+	src := newMemorySQLite(t)
+	dst := newMemorySQLite(t)
+
+	if _, err := src.DB().Exec(`CREATE TABLE widget (id INTEGER PRIMARY KEY, name TEXT)`); err != nil {
+		t.Fatalf("creating source table failed: %v", err)
+	}
+	if _, err := src.DB().Exec(`INSERT INTO widget (id, name) VALUES (1, 'a'), (2, 'b'), (3, 'c')`); err != nil {
+		t.Fatalf("seeding source table failed: %v", err)
+	}
+	if _, err := dst.DB().Exec(`CREATE TABLE widget (id INTEGER PRIMARY KEY, name TEXT)`); err != nil {
+		t.Fatalf("creating destination table failed: %v", err)
+	}
+	if err := ensureMigrationLogTable(dst); err != nil {
+		t.Fatalf("ensureMigrationLogTable failed: %v", err)
+	}
+
+	if err := migrateTable(src, dst, "", "widget", 500); err != nil {
+		t.Fatalf("first migrateTable run failed: %v", err)
+	}
+
+	var count int
+	if err := dst.DB().QueryRow(`SELECT COUNT(*) FROM widget`).Scan(&count); err != nil {
+		t.Fatalf("counting destination rows failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 rows copied, got %d", count)
+	}
+
+	var finishedAt sql.NullString
+	if err := dst.DB().QueryRow(`SELECT finished_at FROM migration_log`).Scan(&finishedAt); err != nil {
+		t.Fatalf("reading migration_log failed: %v", err)
+	}
+	if !finishedAt.Valid {
+		t.Errorf("expected migration_log.finished_at to be set after a successful run")
+	}
+
+	// Re-run against the same, unchanged source: migrateTable should skip the
+	// copy (FinishedAt valid and SourceRowCount unchanged) rather than
+	// re-inserting rows.
+	if _, err := dst.DB().Exec(`DELETE FROM widget`); err != nil {
+		t.Fatalf("clearing destination table failed: %v", err)
+	}
+	if err := migrateTable(src, dst, "", "widget", 500); err != nil {
+		t.Fatalf("second migrateTable run failed: %v", err)
+	}
+	if err := dst.DB().QueryRow(`SELECT COUNT(*) FROM widget`).Scan(&count); err != nil {
+		t.Fatalf("counting destination rows after skip failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected the skipped re-run to leave the manually-cleared table alone, found %d rows", count)
+	}
+}
+
+// TestMigrateTableCommitsAcrossMultipleBatches exercises the batchSize commit
+// boundary in migrateTable: with more rows than fit in a single batch, the
+// table must still land completely and correctly, proving the
+// inBatch >= batchSize checkpoint-and-commit logic doesn't drop or
+// duplicate rows at a batch edge.
+func TestMigrateTableCommitsAcrossMultipleBatches(t *testing.T) {
+	src := newMemorySQLite(t)
+	dst := newMemorySQLite(t)
+
+	if _, err := src.DB().Exec(`CREATE TABLE widget (id INTEGER PRIMARY KEY, name TEXT)`); err != nil {
+		t.Fatalf("creating source table failed: %v", err)
+	}
+	const rowCount = 11
+	for i := 1; i <= rowCount; i++ {
+		if _, err := src.DB().Exec(`INSERT INTO widget (id, name) VALUES (?, ?)`, i, fmt.Sprintf("row-%d", i)); err != nil {
+			t.Fatalf("seeding source row %d failed: %v", i, err)
+		}
+	}
+	if _, err := dst.DB().Exec(`CREATE TABLE widget (id INTEGER PRIMARY KEY, name TEXT)`); err != nil {
+		t.Fatalf("creating destination table failed: %v", err)
+	}
+	if err := ensureMigrationLogTable(dst); err != nil {
+		t.Fatalf("ensureMigrationLogTable failed: %v", err)
+	}
+
+	// batchSize smaller than rowCount forces multiple commit boundaries
+	// partway through the copy.
+	const batchSize = 3
+	if err := migrateTable(src, dst, "", "widget", batchSize); err != nil {
+		t.Fatalf("migrateTable failed: %v", err)
+	}
+
+	var count int
+	if err := dst.DB().QueryRow(`SELECT COUNT(*) FROM widget`).Scan(&count); err != nil {
+		t.Fatalf("counting destination rows failed: %v", err)
+	}
+	if count != rowCount {
+		t.Errorf("expected all %d rows copied across batch boundaries, got %d", rowCount, count)
+	}
+
+	var name string
+	if err := dst.DB().QueryRow(`SELECT name FROM widget WHERE id = ?`, rowCount).Scan(&name); err != nil {
+		t.Fatalf("reading last row failed: %v", err)
+	}
+	if want := fmt.Sprintf("row-%d", rowCount); name != want {
+		t.Errorf("last row's name = %q, want %q", name, want)
+	}
+}
+
+func sortedCopy(in []string) []string {
+	out := append([]string(nil), in...)
+	for i := 1; i < len(out); i++ {
+		for j := i; j > 0 && out[j-1] > out[j]; j-- {
+			out[j-1], out[j] = out[j], out[j-1]
+		}
+	}
+	return out
+}